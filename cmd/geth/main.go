@@ -121,6 +121,8 @@ var (
 		utils.MiningEnabledFlag,
 		utils.MinerThreadsFlag,
 		utils.MinerNotifyFlag,
+		utils.MinerNotifyObjectFlag,
+		utils.MinerNotifyIntervalFlag,
 		utils.MinerGasTargetFlag,
 		utils.MinerGasLimitFlag,
 		utils.MinerGasPriceFlag,
@@ -18,8 +18,11 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
+	"net"
+	"net/url"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -28,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -238,7 +242,7 @@ type parityChainSpec struct {
 				BlockReward            map[string]string `json:"blockReward"`
 				DifficultyBombDelays   map[string]string `json:"difficultyBombDelays"`
 				HomesteadTransition    hexutil.Uint64    `json:"homesteadTransition"`
-				EIP100bTransition      hexutil.Uint64    `json:"eip100bTransition"`
+				EIP100bTransition      *hexutil.Uint64   `json:"eip100bTransition,omitempty"`
 			} `json:"params"`
 		} `json:"Ethash"`
 	} `json:"engine"`
@@ -258,19 +262,19 @@ type parityChainSpec struct {
 		EIP161abcTransition       hexutil.Uint64       `json:"eip161abcTransition"`
 		EIP161dTransition         hexutil.Uint64       `json:"eip161dTransition"`
 		EIP155Transition          hexutil.Uint64       `json:"eip155Transition"`
-		EIP140Transition          hexutil.Uint64       `json:"eip140Transition"`
-		EIP211Transition          hexutil.Uint64       `json:"eip211Transition"`
-		EIP214Transition          hexutil.Uint64       `json:"eip214Transition"`
-		EIP658Transition          hexutil.Uint64       `json:"eip658Transition"`
-		EIP145Transition          hexutil.Uint64       `json:"eip145Transition"`
-		EIP1014Transition         hexutil.Uint64       `json:"eip1014Transition"`
-		EIP1052Transition         hexutil.Uint64       `json:"eip1052Transition"`
-		EIP1283Transition         hexutil.Uint64       `json:"eip1283Transition"`
-		EIP1283DisableTransition  hexutil.Uint64       `json:"eip1283DisableTransition"`
-		EIP1283ReenableTransition hexutil.Uint64       `json:"eip1283ReenableTransition"`
-		EIP1344Transition         hexutil.Uint64       `json:"eip1344Transition"`
-		EIP1884Transition         hexutil.Uint64       `json:"eip1884Transition"`
-		EIP2028Transition         hexutil.Uint64       `json:"eip2028Transition"`
+		EIP140Transition          *hexutil.Uint64      `json:"eip140Transition,omitempty"`
+		EIP211Transition          *hexutil.Uint64      `json:"eip211Transition,omitempty"`
+		EIP214Transition          *hexutil.Uint64      `json:"eip214Transition,omitempty"`
+		EIP658Transition          *hexutil.Uint64      `json:"eip658Transition,omitempty"`
+		EIP145Transition          *hexutil.Uint64      `json:"eip145Transition,omitempty"`
+		EIP1014Transition         *hexutil.Uint64      `json:"eip1014Transition,omitempty"`
+		EIP1052Transition         *hexutil.Uint64      `json:"eip1052Transition,omitempty"`
+		EIP1283Transition         *hexutil.Uint64      `json:"eip1283Transition,omitempty"`
+		EIP1283DisableTransition  *hexutil.Uint64      `json:"eip1283DisableTransition,omitempty"`
+		EIP1283ReenableTransition *hexutil.Uint64      `json:"eip1283ReenableTransition,omitempty"`
+		EIP1344Transition         *hexutil.Uint64      `json:"eip1344Transition,omitempty"`
+		EIP1884Transition         *hexutil.Uint64      `json:"eip1884Transition,omitempty"`
+		EIP2028Transition         *hexutil.Uint64      `json:"eip2028Transition,omitempty"`
 	} `json:"params"`
 
 	Genesis struct {
@@ -296,9 +300,12 @@ type parityChainSpec struct {
 // parityChainSpecAccount is the prefunded genesis account and/or precompiled
 // contract definition.
 type parityChainSpecAccount struct {
-	Balance math2.HexOrDecimal256   `json:"balance"`
-	Nonce   math2.HexOrDecimal64    `json:"nonce,omitempty"`
-	Builtin *parityChainSpecBuiltin `json:"builtin,omitempty"`
+	Balance     math2.HexOrDecimal256       `json:"balance"`
+	Nonce       math2.HexOrDecimal64        `json:"nonce,omitempty"`
+	Code        hexutil.Bytes               `json:"code,omitempty"`
+	Storage     map[common.Hash]common.Hash `json:"storage,omitempty"`
+	Constructor hexutil.Bytes               `json:"constructor,omitempty"`
+	Builtin     *parityChainSpecBuiltin     `json:"builtin,omitempty"`
 }
 
 // parityChainSpecBuiltin is the precompiled contract definition.
@@ -361,6 +368,37 @@ type parityChainSpecVersionedPricing struct {
 	Info  string                           `json:"info,omitempty"`
 }
 
+// parityBootnode validates a bootnode and returns it as an enode URL, the only
+// format Parity understands. Enode URLs are kept verbatim: host names are not
+// resolved, the node is checked against a placeholder address instead so that
+// the spec doesn't depend on DNS at conversion time. Node records carry their
+// IP address, so they are converted without any lookup.
+func parityBootnode(rawurl string) (string, error) {
+	check, verbatim := rawurl, strings.HasPrefix(rawurl, "enode://")
+	if verbatim {
+		u, err := url.Parse(rawurl)
+		if err != nil {
+			return "", err
+		}
+		if u.Hostname() == "" {
+			return "", errors.New("missing endpoint")
+		}
+		u.Host = net.JoinHostPort("127.0.0.1", u.Port())
+		check = u.String()
+	}
+	node, err := enode.Parse(enode.ValidSchemes, check)
+	if err != nil {
+		return "", err
+	}
+	if node.IP() == nil || node.TCP() == 0 {
+		return "", errors.New("missing endpoint")
+	}
+	if verbatim {
+		return rawurl, nil
+	}
+	return node.URLv4(), nil
+}
+
 // newParityChainSpec converts a go-ethereum genesis block into a Parity specific
 // chain specification format.
 func newParityChainSpec(network string, genesis *core.Genesis, bootnodes []string) (*parityChainSpec, error) {
@@ -371,9 +409,16 @@ func newParityChainSpec(network string, genesis *core.Genesis, bootnodes []strin
 	// Reconstruct the chain spec in Parity's format
 	spec := &parityChainSpec{
 		Name:    network,
-		Nodes:   bootnodes,
+		Nodes:   make([]string, 0, len(bootnodes)),
 		Datadir: strings.ToLower(network),
 	}
+	for _, node := range bootnodes {
+		enodeURL, err := parityBootnode(node)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bootnode %q: %v", node, err)
+		}
+		spec.Nodes = append(spec.Nodes, enodeURL)
+	}
 	spec.Engine.Ethash.Params.BlockReward = make(map[string]string)
 	spec.Engine.Ethash.Params.DifficultyBombDelays = make(map[string]string)
 	// Frontier
@@ -440,6 +485,8 @@ func newParityChainSpec(network string, genesis *core.Genesis, bootnodes []strin
 		spec.Accounts[common.UnprefixedAddress(address)] = &parityChainSpecAccount{
 			Balance: bal,
 			Nonce:   math2.HexOrDecimal64(account.Nonce),
+			Code:    account.Code,
+			Storage: account.Storage,
 		}
 	}
 	spec.setPrecompile(1, &parityChainSpecBuiltin{Name: "ecrecover",
@@ -562,32 +609,107 @@ func (spec *parityChainSpec) setByzantium(num *big.Int) {
 	spec.Engine.Ethash.Params.BlockReward[hexutil.EncodeBig(num)] = hexutil.EncodeBig(ethash.ByzantiumBlockReward)
 	spec.Engine.Ethash.Params.DifficultyBombDelays[hexutil.EncodeBig(num)] = hexutil.EncodeUint64(3000000)
 	n := hexutil.Uint64(num.Uint64())
-	spec.Engine.Ethash.Params.EIP100bTransition = n
-	spec.Params.EIP140Transition = n
-	spec.Params.EIP211Transition = n
-	spec.Params.EIP214Transition = n
-	spec.Params.EIP658Transition = n
+	spec.Engine.Ethash.Params.EIP100bTransition = &n
+	spec.Params.EIP140Transition = &n
+	spec.Params.EIP211Transition = &n
+	spec.Params.EIP214Transition = &n
+	spec.Params.EIP658Transition = &n
 }
 
 func (spec *parityChainSpec) setConstantinople(num *big.Int) {
 	spec.Engine.Ethash.Params.BlockReward[hexutil.EncodeBig(num)] = hexutil.EncodeBig(ethash.ConstantinopleBlockReward)
 	spec.Engine.Ethash.Params.DifficultyBombDelays[hexutil.EncodeBig(num)] = hexutil.EncodeUint64(2000000)
 	n := hexutil.Uint64(num.Uint64())
-	spec.Params.EIP145Transition = n
-	spec.Params.EIP1014Transition = n
-	spec.Params.EIP1052Transition = n
-	spec.Params.EIP1283Transition = n
+	spec.Params.EIP145Transition = &n
+	spec.Params.EIP1014Transition = &n
+	spec.Params.EIP1052Transition = &n
+	spec.Params.EIP1283Transition = &n
 }
 
 func (spec *parityChainSpec) setConstantinopleFix(num *big.Int) {
-	spec.Params.EIP1283DisableTransition = hexutil.Uint64(num.Uint64())
+	n := hexutil.Uint64(num.Uint64())
+	spec.Params.EIP1283DisableTransition = &n
 }
 
 func (spec *parityChainSpec) setIstanbul(num *big.Int) {
-	spec.Params.EIP1344Transition = hexutil.Uint64(num.Uint64())
-	spec.Params.EIP1884Transition = hexutil.Uint64(num.Uint64())
-	spec.Params.EIP2028Transition = hexutil.Uint64(num.Uint64())
-	spec.Params.EIP1283ReenableTransition = hexutil.Uint64(num.Uint64())
+	n := hexutil.Uint64(num.Uint64())
+	spec.Params.EIP1344Transition = &n
+	spec.Params.EIP1884Transition = &n
+	spec.Params.EIP2028Transition = &n
+	spec.Params.EIP1283ReenableTransition = &n
+}
+
+// toGethGenesis converts a Parity specific chain specification back into a
+// go-ethereum genesis block. It is the inverse of newParityChainSpec, so only
+// the Ethash engine is supported.
+//
+// Note, Parity treats a missing transition as a disabled fork, so any fork from
+// Byzantium onwards without a transition is left unset in the chain config.
+func (spec *parityChainSpec) toGethGenesis() (*core.Genesis, error) {
+	// Only ethash is currently supported between go-ethereum and Parity
+	if spec.Engine.Ethash.Params.MinimumDifficulty == nil {
+		return nil, errors.New("unsupported consensus engine")
+	}
+	// Parity falls back to the network id if no chain id is specified
+	chainID := spec.Params.ChainID
+	if chainID == 0 {
+		chainID = spec.Params.NetworkID
+	}
+	block := func(num hexutil.Uint64) *big.Int {
+		return new(big.Int).SetUint64(uint64(num))
+	}
+	optional := func(num *hexutil.Uint64) *big.Int {
+		if num == nil {
+			return nil
+		}
+		return block(*num)
+	}
+	config := &params.ChainConfig{
+		ChainID:             block(chainID),
+		HomesteadBlock:      block(spec.Engine.Ethash.Params.HomesteadTransition),
+		EIP150Block:         block(spec.Params.EIP150Transition),
+		EIP155Block:         block(spec.Params.EIP155Transition),
+		EIP158Block:         block(spec.Params.EIP161abcTransition),
+		ByzantiumBlock:      optional(spec.Params.EIP140Transition),
+		ConstantinopleBlock: optional(spec.Params.EIP145Transition),
+		PetersburgBlock:     optional(spec.Params.EIP1283DisableTransition),
+		IstanbulBlock:       optional(spec.Params.EIP1344Transition),
+		Ethash:              new(params.EthashConfig),
+	}
+	if err := config.CheckConfigForkOrder(); err != nil {
+		return nil, err
+	}
+	genesis := &core.Genesis{
+		Config:     config,
+		Nonce:      spec.Genesis.Seal.Ethereum.Nonce.Uint64(),
+		Timestamp:  uint64(spec.Genesis.Timestamp),
+		ExtraData:  spec.Genesis.ExtraData,
+		GasLimit:   uint64(spec.Genesis.GasLimit),
+		Difficulty: (*big.Int)(spec.Genesis.Difficulty),
+		Mixhash:    common.BytesToHash(spec.Genesis.Seal.Ethereum.MixHash),
+		Coinbase:   spec.Genesis.Author,
+		ParentHash: spec.Genesis.ParentHash,
+		Alloc:      make(core.GenesisAlloc),
+	}
+	for address, account := range spec.Accounts {
+		balance := (*big.Int)(&account.Balance)
+
+		// Constructors are executed by Parity at genesis, geth can't do that
+		if len(account.Constructor) > 0 {
+			return nil, fmt.Errorf("unsupported constructor for account %x", address)
+		}
+		// Skip builtins which Parity lists without any state, geth has them implicitly
+		if account.Builtin != nil && balance.Sign() == 0 && account.Nonce == 0 && len(account.Code) == 0 && len(account.Storage) == 0 {
+			continue
+		}
+		genesis.Alloc[common.Address(address)] = core.GenesisAccount{
+			Balance: new(big.Int).Set(balance),
+			Nonce:   uint64(account.Nonce),
+			Code:    account.Code,
+			Storage: account.Storage,
+		}
+	}
+	return genesis, nil
 }
 
 // pyEthereumGenesisSpec represents the genesis specification format used by the
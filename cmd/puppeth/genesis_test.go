@@ -25,7 +25,10 @@ import (
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 // Tests the go-ethereum to Aleth chainspec conversion for the Stureby testnet.
@@ -67,8 +70,39 @@ func TestAlethSturebyConverter(t *testing.T) {
 	}
 }
 
-// Tests the go-ethereum to Parity chainspec conversion for the Stureby testnet.
+// Tests the go-ethereum to Parity chainspec conversion for the Stureby testnet,
+// both as is and with a pre-deployed contract.
 func TestParitySturebyConverter(t *testing.T) {
+	for _, fixture := range []string{"stureby", "stureby_contract"} {
+		blob, err := ioutil.ReadFile("testdata/" + fixture + "_geth.json")
+		if err != nil {
+			t.Fatalf("could not read file: %v", err)
+		}
+		var genesis core.Genesis
+		if err := json.Unmarshal(blob, &genesis); err != nil {
+			t.Fatalf("%s: failed parsing genesis: %v", fixture, err)
+		}
+		spec, err := newParityChainSpec("stureby", &genesis, []string{})
+		if err != nil {
+			t.Fatalf("%s: failed creating chainspec: %v", fixture, err)
+		}
+		enc, err := json.MarshalIndent(spec, "", "  ")
+		if err != nil {
+			t.Fatalf("%s: failed encoding chainspec: %v", fixture, err)
+		}
+		expBlob, err := ioutil.ReadFile("testdata/" + fixture + "_parity.json")
+		if err != nil {
+			t.Fatalf("could not read file: %v", err)
+		}
+		if !bytes.Equal(expBlob, enc) {
+			t.Fatalf("%s: chainspec mismatch", fixture)
+		}
+	}
+}
+
+// Tests that forks disabled in go-ethereum are omitted from the Parity chainspec
+// and that missing Parity transitions are imported as disabled forks.
+func TestParityPartialForks(t *testing.T) {
 	blob, err := ioutil.ReadFile("testdata/stureby_geth.json")
 	if err != nil {
 		t.Fatalf("could not read file: %v", err)
@@ -77,6 +111,10 @@ func TestParitySturebyConverter(t *testing.T) {
 	if err := json.Unmarshal(blob, &genesis); err != nil {
 		t.Fatalf("failed parsing genesis: %v", err)
 	}
+	genesis.Config.ConstantinopleBlock = nil
+	genesis.Config.PetersburgBlock = nil
+	genesis.Config.IstanbulBlock = nil
+
 	spec, err := newParityChainSpec("stureby", &genesis, []string{})
 	if err != nil {
 		t.Fatalf("failed creating chainspec: %v", err)
@@ -85,11 +123,186 @@ func TestParitySturebyConverter(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed encoding chainspec: %v", err)
 	}
-	expBlob, err := ioutil.ReadFile("testdata/stureby_parity.json")
+	expBlob, err := ioutil.ReadFile("testdata/stureby_byzantium_parity.json")
 	if err != nil {
 		t.Fatalf("could not read file: %v", err)
 	}
 	if !bytes.Equal(expBlob, enc) {
 		t.Fatalf("chainspec mismatch")
 	}
+	var imported parityChainSpec
+	if err := json.Unmarshal(expBlob, &imported); err != nil {
+		t.Fatalf("failed parsing chainspec: %v", err)
+	}
+	have, err := imported.toGethGenesis()
+	if err != nil {
+		t.Fatalf("failed creating genesis: %v", err)
+	}
+	haveConfig, _ := json.Marshal(have.Config)
+	wantConfig, _ := json.Marshal(genesis.Config)
+	if !bytes.Equal(haveConfig, wantConfig) {
+		t.Errorf("chain config mismatch:\nhave %s\nwant %s", haveConfig, wantConfig)
+	}
+}
+
+// Tests that Parity chainspecs with transitions out of go-ethereum's fork order
+// are rejected on import.
+func TestParityInvalidForkOrder(t *testing.T) {
+	blob, err := ioutil.ReadFile("testdata/stureby_byzantium_parity.json")
+	if err != nil {
+		t.Fatalf("could not read file: %v", err)
+	}
+	var spec parityChainSpec
+	if err := json.Unmarshal(blob, &spec); err != nil {
+		t.Fatalf("failed parsing chainspec: %v", err)
+	}
+	// Petersburg without Constantinople can't be represented in go-ethereum
+	petersburg := hexutil.Uint64(0x9c40)
+	spec.Params.EIP1283DisableTransition = &petersburg
+
+	if genesis, err := spec.toGethGenesis(); err == nil {
+		t.Fatalf("expected fork ordering failure, got config %v", genesis.Config)
+	}
+}
+
+// Tests that bootnodes are validated and emitted as enode URLs when creating a
+// Parity chainspec.
+func TestParityBootnodes(t *testing.T) {
+	blob, err := ioutil.ReadFile("testdata/stureby_geth.json")
+	if err != nil {
+		t.Fatalf("could not read file: %v", err)
+	}
+	var genesis core.Genesis
+	if err := json.Unmarshal(blob, &genesis); err != nil {
+		t.Fatalf("failed parsing genesis: %v", err)
+	}
+	tests := []struct {
+		bootnodes []string
+		nodes     []string
+		fail      bool
+	}{
+		// Valid enode URLs are retained
+		{
+			bootnodes: params.MainnetBootnodes[:2],
+			nodes:     params.MainnetBootnodes[:2],
+		},
+		// Node URLs are not reformatted
+		{
+			bootnodes: []string{"enode://" + strings.ToUpper(params.MainnetBootnodes[0][8:136]) + params.MainnetBootnodes[0][136:]},
+			nodes:     []string{"enode://" + strings.ToUpper(params.MainnetBootnodes[0][8:136]) + params.MainnetBootnodes[0][136:]},
+		},
+		// Host names are not resolved
+		{
+			bootnodes: []string{params.MainnetBootnodes[0][:137] + "bootnode.invalid:30303"},
+			nodes:     []string{params.MainnetBootnodes[0][:137] + "bootnode.invalid:30303"},
+		},
+		// Node records are converted to enode URLs
+		{
+			bootnodes: params.V5Bootnodes[:1],
+			nodes:     []string{enode.MustParse(params.V5Bootnodes[0]).URLv4()},
+		},
+		// Node URLs without a port are rejected
+		{
+			bootnodes: []string{params.MainnetBootnodes[0][:137] + "bootnode.invalid"},
+			fail:      true,
+		},
+		// Malformed node ids are rejected
+		{
+			bootnodes: []string{params.MainnetBootnodes[0], "enode://deadbeef@127.0.0.1:30303"},
+			fail:      true,
+		},
+		// Node records without an endpoint are rejected
+		{
+			bootnodes: []string{params.MainnetBootnodes[0][:strings.Index(params.MainnetBootnodes[0], "@")]},
+			fail:      true,
+		},
+		// Non enode URLs are rejected
+		{
+			bootnodes: []string{"http://127.0.0.1:30303"},
+			fail:      true,
+		},
+	}
+	for i, tt := range tests {
+		spec, err := newParityChainSpec("stureby", &genesis, tt.bootnodes)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("test %d: expected failure, got nodes %v", i, spec.Nodes)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: failed creating chainspec: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(spec.Nodes, tt.nodes) {
+			t.Errorf("test %d: nodes mismatch: have %v, want %v", i, spec.Nodes, tt.nodes)
+		}
+	}
+}
+
+// Tests the Parity to go-ethereum genesis conversion for the Stureby testnet,
+// both as is and with a pre-deployed contract.
+func TestParitySturebyImporter(t *testing.T) {
+	for _, fixture := range []string{"stureby", "stureby_contract"} {
+		blob, err := ioutil.ReadFile("testdata/" + fixture + "_geth.json")
+		if err != nil {
+			t.Fatalf("could not read file: %v", err)
+		}
+		var want core.Genesis
+		if err := json.Unmarshal(blob, &want); err != nil {
+			t.Fatalf("%s: failed parsing genesis: %v", fixture, err)
+		}
+		blob, err = ioutil.ReadFile("testdata/" + fixture + "_parity.json")
+		if err != nil {
+			t.Fatalf("could not read file: %v", err)
+		}
+		var spec parityChainSpec
+		if err := json.Unmarshal(blob, &spec); err != nil {
+			t.Fatalf("%s: failed parsing chainspec: %v", fixture, err)
+		}
+		genesis, err := spec.toGethGenesis()
+		if err != nil {
+			t.Fatalf("%s: failed creating genesis: %v", fixture, err)
+		}
+		if have, want := genesis.ToBlock(nil).Hash(), want.ToBlock(nil).Hash(); have != want {
+			t.Errorf("%s: genesis hash mismatch: have %x, want %x", fixture, have, want)
+		}
+		haveConfig, _ := json.Marshal(genesis.Config)
+		wantConfig, _ := json.Marshal(want.Config)
+		if !bytes.Equal(haveConfig, wantConfig) {
+			t.Errorf("%s: chain config mismatch:\nhave %s\nwant %s", fixture, haveConfig, wantConfig)
+		}
+	}
+}
+
+// Tests that Parity specific account and chain id semantics are honoured, or
+// rejected if go-ethereum can't represent them.
+func TestParityImporterFallbacks(t *testing.T) {
+	blob, err := ioutil.ReadFile("testdata/stureby_contract_parity.json")
+	if err != nil {
+		t.Fatalf("could not read file: %v", err)
+	}
+	// Parity uses the network id if the chain id is missing
+	var spec parityChainSpec
+	if err := json.Unmarshal(blob, &spec); err != nil {
+		t.Fatalf("failed parsing chainspec: %v", err)
+	}
+	spec.Params.ChainID = 0
+
+	genesis, err := spec.toGethGenesis()
+	if err != nil {
+		t.Fatalf("failed creating genesis: %v", err)
+	}
+	if have, want := genesis.Config.ChainID.Uint64(), uint64(spec.Params.NetworkID); have != want {
+		t.Errorf("chain id mismatch: have %d, want %d", have, want)
+	}
+	// Accounts deployed by a constructor can't be imported
+	for _, account := range spec.Accounts {
+		if len(account.Code) > 0 {
+			account.Constructor, account.Code = account.Code, nil
+		}
+	}
+	if _, err := spec.toGethGenesis(); err == nil {
+		t.Errorf("expected failure for account constructor")
+	}
 }
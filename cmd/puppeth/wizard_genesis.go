@@ -141,11 +141,11 @@ func (w *wizard) makeGenesis() {
 	w.conf.flush()
 }
 
-// importGenesis imports a Geth genesis spec into puppeth.
+// importGenesis imports a Geth genesis spec or a Parity chainspec into puppeth.
 func (w *wizard) importGenesis() {
 	// Request the genesis JSON spec URL from the user
 	fmt.Println()
-	fmt.Println("Where's the genesis file? (local file or http/https url)")
+	fmt.Println("Where's the genesis file or Parity chainspec? (local file or http/https url)")
 	url := w.readURL()
 
 	// Convert the various allowed URLs to a reader stream
@@ -176,15 +176,29 @@ func (w *wizard) importGenesis() {
 		log.Error("Unsupported genesis URL scheme", "scheme", url.Scheme)
 		return
 	}
-	// Parse the genesis file and inject it successful
-	var genesis core.Genesis
-	if err := json.NewDecoder(reader).Decode(&genesis); err != nil {
-		log.Error("Invalid genesis spec", "err", err)
+	blob, err := ioutil.ReadAll(reader)
+	if err != nil {
+		log.Error("Failed to read genesis spec", "err", err)
 		return
 	}
+	// Parse the genesis file as a Geth spec, falling back to a Parity chainspec
+	genesis := new(core.Genesis)
+	if err := json.Unmarshal(blob, genesis); err != nil {
+		var spec parityChainSpec
+		if json.Unmarshal(blob, &spec) != nil {
+			log.Error("Invalid genesis spec", "err", err)
+			return
+		}
+		var perr error
+		if genesis, perr = spec.toGethGenesis(); perr != nil {
+			log.Error("Invalid genesis spec", "geth", err, "parity", perr)
+			return
+		}
+		log.Info("Converted Parity chainspec", "name", spec.Name)
+	}
 	log.Info("Imported genesis block")
 
-	w.conf.Genesis = &genesis
+	w.conf.Genesis = genesis
 	w.conf.flush()
 }
 
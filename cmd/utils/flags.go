@@ -427,6 +427,14 @@ var (
 		Name:  "miner.notify",
 		Usage: "Comma separated HTTP URL list to notify of new work packages",
 	}
+	MinerNotifyObjectFlag = cli.BoolFlag{
+		Name:  "miner.notify.object",
+		Usage: "Notify with a JSON object of named work package fields instead of an array",
+	}
+	MinerNotifyIntervalFlag = cli.DurationFlag{
+		Name:  "miner.notify.interval",
+		Usage: "Minimum time between two new work notifications (0 = no limit)",
+	}
 	MinerGasTargetFlag = cli.Uint64Flag{
 		Name:  "miner.gastarget",
 		Usage: "Target gas floor for mined blocks",
@@ -1353,6 +1361,12 @@ func setEthash(ctx *cli.Context, cfg *ethconfig.Config) {
 	if ctx.GlobalIsSet(EthashDatasetsLockMmapFlag.Name) {
 		cfg.Ethash.DatasetsLockMmap = ctx.GlobalBool(EthashDatasetsLockMmapFlag.Name)
 	}
+	if ctx.GlobalIsSet(MinerNotifyObjectFlag.Name) {
+		cfg.Ethash.NotifyObject = ctx.GlobalBool(MinerNotifyObjectFlag.Name)
+	}
+	if ctx.GlobalIsSet(MinerNotifyIntervalFlag.Name) {
+		cfg.Ethash.NotifyInterval = ctx.GlobalDuration(MinerNotifyIntervalFlag.Name)
+	}
 }
 
 func setMiner(ctx *cli.Context, cfg *miner.Config) {
@@ -18,6 +18,7 @@ package ethash
 
 import (
 	"errors"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -39,24 +40,47 @@ type API struct {
 //   result[2] - 32 bytes hex encoded boundary condition ("target"), 2^256/difficulty
 //   result[3] - hex encoded block number
 func (api *API) GetWork() ([4]string, error) {
+	work, err := api.GetWorkDetailed()
+	if err != nil {
+		return [4]string{}, err
+	}
+	return work.array(), nil
+}
+
+// GetWorkDetailed returns a work package for external miner, the same as
+// GetWork but with named fields instead of a positional array.
+func (api *API) GetWorkDetailed() (*WorkPackage, error) {
 	if api.ethash.remote == nil {
-		return [4]string{}, errors.New("not supported")
+		return nil, errors.New("not supported")
 	}
 
+	fetchTimeout := api.ethash.config.FetchTimeout
+	if fetchTimeout == 0 {
+		fetchTimeout = remoteSealerFetchTimeout
+	}
+	// Note the reply channels are buffered, so if the request times out, a late
+	// reply is dropped along with them instead of blocking the sealer.
 	var (
-		workCh = make(chan [4]string, 1)
-		errc   = make(chan error, 1)
+		workCh  = make(chan *WorkPackage, 1)
+		errc    = make(chan error, 1)
+		timeout = time.NewTimer(fetchTimeout)
 	)
+	defer timeout.Stop()
+
 	select {
 	case api.ethash.remote.fetchWorkCh <- &sealWork{errc: errc, res: workCh}:
 	case <-api.ethash.remote.exitCh:
-		return [4]string{}, errEthashStopped
+		return nil, errEthashStopped
+	case <-timeout.C:
+		return nil, errFetchWorkTimeout
 	}
 	select {
 	case work := <-workCh:
 		return work, nil
 	case err := <-errc:
-		return [4]string{}, err
+		return nil, err
+	case <-timeout.C:
+		return nil, errFetchWorkTimeout
 	}
 }
 
@@ -64,8 +88,29 @@ func (api *API) GetWork() ([4]string, error) {
 // It returns an indication if the work was accepted.
 // Note either an invalid solution, a stale work a non-existent work will return false.
 func (api *API) SubmitWork(nonce types.BlockNonce, hash, digest common.Hash) bool {
+	return api.submitWork(nonce, hash, digest) == nil
+}
+
+// SubmitWorkResult is the outcome of a POW solution submitted by an external miner.
+type SubmitWorkResult struct {
+	Accepted bool   `json:"accepted"`
+	Reason   string `json:"reason,omitempty"` // Reason of the rejection, if any
+}
+
+// SubmitWorkVerbose can be used by external miner to submit their POW solution.
+// Unlike SubmitWork, it also returns the reason why the work was rejected.
+func (api *API) SubmitWorkVerbose(nonce types.BlockNonce, hash, digest common.Hash) *SubmitWorkResult {
+	if err := api.submitWork(nonce, hash, digest); err != nil {
+		return &SubmitWorkResult{Reason: err.Error()}
+	}
+	return &SubmitWorkResult{Accepted: true}
+}
+
+// submitWork hands a POW solution over to the remote sealer, returning the
+// reason of the rejection or nil if the work was accepted.
+func (api *API) submitWork(nonce types.BlockNonce, hash, digest common.Hash) error {
 	if api.ethash.remote == nil {
-		return false
+		return errors.New("not supported")
 	}
 
 	var errc = make(chan error, 1)
@@ -77,10 +122,9 @@ func (api *API) SubmitWork(nonce types.BlockNonce, hash, digest common.Hash) boo
 		errc:      errc,
 	}:
 	case <-api.ethash.remote.exitCh:
-		return false
+		return errEthashStopped
 	}
-	err := <-errc
-	return err == nil
+	return <-errc
 }
 
 // SubmitHashrate can be used for remote miners to submit their hash rate.
@@ -88,7 +132,7 @@ func (api *API) SubmitWork(nonce types.BlockNonce, hash, digest common.Hash) boo
 // which submit work through this node.
 //
 // It accepts the miner hash rate and an identifier which must be unique
-// between nodes.
+// between nodes. Submitting a zero rate removes the miner from the total.
 func (api *API) SubmitHashRate(rate hexutil.Uint64, id common.Hash) bool {
 	if api.ethash.remote == nil {
 		return false
@@ -110,3 +154,19 @@ func (api *API) SubmitHashRate(rate hexutil.Uint64, id common.Hash) bool {
 func (api *API) GetHashrate() uint64 {
 	return uint64(api.ethash.Hashrate())
 }
+
+// DetailedHashrate is the hash rate of the node split by its origin.
+type DetailedHashrate struct {
+	Local  uint64 `json:"local"`  // Hash rate of the local CPU miner
+	Remote uint64 `json:"remote"` // Total hash rate submitted by remote miners
+}
+
+// GetHashrateDetailed returns the current hashrate of the local CPU miner and
+// of the remote miners separately. Remote miners which stopped submitting their
+// hash rate are dropped from the total after a short while.
+func (api *API) GetHashrateDetailed() *DetailedHashrate {
+	return &DetailedHashrate{
+		Local:  uint64(api.ethash.hashrate.Rate1()),
+		Remote: api.ethash.remoteHashrate(),
+	}
+}
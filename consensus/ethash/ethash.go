@@ -48,7 +48,7 @@ var (
 	two256 = new(big.Int).Exp(big.NewInt(2), big.NewInt(256), big.NewInt(0))
 
 	// sharedEthash is a full instance that can be shared between multiple users.
-	sharedEthash = New(Config{"", 3, 0, false, "", 1, 0, false, ModeNormal, nil}, nil, false)
+	sharedEthash = New(Config{"", 3, 0, false, "", 1, 0, false, ModeNormal, false, 0, 0, nil}, nil, false)
 
 	// algorithmRevision is the data structure version used for file naming.
 	algorithmRevision = 23
@@ -411,6 +411,19 @@ type Config struct {
 	DatasetsLockMmap bool
 	PowMode          Mode
 
+	// When set, notifications sent by the remote sealer will be a JSON object
+	// with named fields instead of the positional work package array.
+	NotifyObject bool
+
+	// Minimum time between two notifications sent by the remote sealer. Work
+	// packages arriving faster are coalesced, the latest one being sent once
+	// the interval elapsed. Zero disables the limit.
+	NotifyInterval time.Duration
+
+	// Maximum time remote miners wait on the sealer to hand out work. Zero
+	// uses the 10 second default.
+	FetchTimeout time.Duration
+
 	Log log.Logger `toml:"-"`
 }
 
@@ -535,6 +548,32 @@ func NewShared() *Ethash {
 	return &Ethash{shared: sharedEthash}
 }
 
+// PauseRemoteSealer stops notifying remote miners of new work packages and
+// rejects their work fetches and submissions until ResumeRemoteSealer is called.
+func (ethash *Ethash) PauseRemoteSealer() error {
+	return ethash.setRemotePaused(true)
+}
+
+// ResumeRemoteSealer resumes serving remote miners after PauseRemoteSealer,
+// notifying them of the current work package if any.
+func (ethash *Ethash) ResumeRemoteSealer() error {
+	return ethash.setRemotePaused(false)
+}
+
+// setRemotePaused pauses or resumes the remote sealer, returning once the
+// transition took effect.
+func (ethash *Ethash) setRemotePaused(paused bool) error {
+	if ethash.remote == nil {
+		return errors.New("not supported")
+	}
+	select {
+	case ethash.remote.pauseCh <- paused:
+		return nil
+	case <-ethash.remote.exitCh:
+		return errEthashStopped
+	}
+}
+
 // Close closes the exit channel to notify all backend threads exiting.
 func (ethash *Ethash) Close() error {
 	var err error
@@ -638,21 +677,25 @@ func (ethash *Ethash) SetThreads(threads int) {
 // Note the returned hashrate includes local hashrate, but also includes the total
 // hashrate of all remote miner.
 func (ethash *Ethash) Hashrate() float64 {
+	return ethash.hashrate.Rate1() + float64(ethash.remoteHashrate())
+}
+
+// remoteHashrate returns the total hash rate submitted by the remote miners
+// which are still actively reporting.
+func (ethash *Ethash) remoteHashrate() uint64 {
 	// Short circuit if we are run the ethash in normal/test mode.
 	if ethash.config.PowMode != ModeNormal && ethash.config.PowMode != ModeTest {
-		return ethash.hashrate.Rate1()
+		return 0
 	}
 	var res = make(chan uint64, 1)
 
 	select {
 	case ethash.remote.fetchRateCh <- res:
 	case <-ethash.remote.exitCh:
-		// Return local hashrate only if ethash is stopped.
-		return ethash.hashrate.Rate1()
+		// Report no remote hashrate if ethash is stopped.
+		return 0
 	}
-
-	// Gather total submitted hash rate of remote sealers.
-	return ethash.hashrate.Rate1() + float64(<-res)
+	return <-res
 }
 
 // APIs implements consensus.Engine, returning the user facing RPC APIs.
@@ -27,7 +27,9 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/mclock"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
 )
 
 // Tests that ethash works correctly in test mode.
@@ -113,6 +115,16 @@ func TestRemoteSealer(t *testing.T) {
 	if work, err = api.GetWork(); err != nil || work[0] != sealhash.Hex() {
 		t.Error("expect to return a mining work has same hash")
 	}
+	detailed, err := api.GetWorkDetailed()
+	if err != nil {
+		t.Fatalf("failed to retrieve detailed work: %v", err)
+	}
+	if detailed.array() != work {
+		t.Errorf("detailed work mismatch: have %v, want %v", detailed.array(), work)
+	}
+	if detailed.PowHash != sealhash || detailed.Number.ToInt().Cmp(header.Number) != 0 {
+		t.Errorf("detailed work fields mismatch: have hash %x number %v", detailed.PowHash, detailed.Number)
+	}
 
 	if res := api.SubmitWork(types.BlockNonce{}, sealhash, common.Hash{}); res {
 		t.Error("expect to return false when submit a fake solution")
@@ -128,6 +140,23 @@ func TestRemoteSealer(t *testing.T) {
 	}
 }
 
+// Tests that fetching work from an unresponsive remote sealer times out.
+func TestRemoteSealerFetchTimeout(t *testing.T) {
+	ethash := newTestRemoteSealer(t, nil, mclock.System{})
+	ethash.config.FetchTimeout = 100 * time.Millisecond
+	defer ethash.Close()
+
+	// Stall the event loop on a hash rate request nobody reads.
+	stall := make(chan uint64)
+	ethash.remote.fetchRateCh <- stall
+	defer func() { <-stall }()
+
+	api := &API{ethash}
+	if _, err := api.GetWork(); err != errFetchWorkTimeout {
+		t.Errorf("fetch error mismatch: have %v, want %v", err, errFetchWorkTimeout)
+	}
+}
+
 func TestHashRate(t *testing.T) {
 	var (
 		hashrate = []hexutil.Uint64{100, 200, 300}
@@ -151,6 +180,40 @@ func TestHashRate(t *testing.T) {
 	if tot := ethash.Hashrate(); tot != float64(expect) {
 		t.Error("expect total hashrate should be same")
 	}
+	if rate := api.GetHashrateDetailed(); rate.Local != 0 || rate.Remote != expect {
+		t.Errorf("detailed hashrate mismatch: have %+v, want remote %d", rate, expect)
+	}
+}
+
+// Tests that remote miners which stopped reporting are dropped from the total
+// hash rate, and that a zero rate removes a miner explicitly.
+func TestHashRateExpiry(t *testing.T) {
+	clock := new(mclock.Simulated)
+
+	ethash := newTestRemoteSealer(t, nil, clock)
+	ethash.config.PowMode = ModeTest
+	ethash.hashrate = metrics.NewMeterForced()
+	defer ethash.Close()
+
+	api := &API{ethash}
+	api.SubmitHashRate(100, common.HexToHash("a"))
+	api.SubmitHashRate(200, common.HexToHash("b"))
+	if tot := ethash.Hashrate(); tot != 300 {
+		t.Errorf("total hashrate mismatch: have %v, want %v", tot, 300)
+	}
+	// Refresh one of the miners and let the other one go stale.
+	clock.Run(hashrateTTL / 2)
+	api.SubmitHashRate(200, common.HexToHash("b"))
+	clock.Run(hashrateTTL/2 + time.Second)
+
+	if tot := ethash.Hashrate(); tot != 200 {
+		t.Errorf("total hashrate mismatch after expiry: have %v, want %v", tot, 200)
+	}
+	// Remove the remaining miner explicitly.
+	api.SubmitHashRate(0, common.HexToHash("b"))
+	if tot := ethash.Hashrate(); tot != 0 {
+		t.Errorf("total hashrate mismatch after removal: have %v, want %v", tot, 0)
+	}
 }
 
 func TestClosedRemoteSealer(t *testing.T) {
@@ -166,4 +229,22 @@ func TestClosedRemoteSealer(t *testing.T) {
 	if res := api.SubmitHashRate(hexutil.Uint64(100), common.HexToHash("a")); res {
 		t.Error("expect to return false when submit hashrate to a stopped ethash")
 	}
+	if _, err := api.GetWorkDetailed(); err != errEthashStopped {
+		t.Error("expect to return an error to indicate ethash is stopped")
+	}
+	if res := api.SubmitWork(types.BlockNonce{}, common.Hash{}, common.Hash{}); res {
+		t.Error("expect to return false when submit work to a stopped ethash")
+	}
+	if res := api.SubmitWorkVerbose(types.BlockNonce{}, common.Hash{}, common.Hash{}); res.Accepted || res.Reason != errEthashStopped.Error() {
+		t.Errorf("expect to return the stopped error as rejection reason, got %+v", res)
+	}
+	if rate := api.GetHashrateDetailed(); rate.Remote != 0 {
+		t.Errorf("expect to return no remote hashrate for a stopped ethash, got %d", rate.Remote)
+	}
+	if err := ethash.PauseRemoteSealer(); err != errEthashStopped {
+		t.Error("expect to return an error to indicate ethash is stopped")
+	}
+	if err := ethash.ResumeRemoteSealer(); err != errEthashStopped {
+		t.Error("expect to return an error to indicate ethash is stopped")
+	}
 }
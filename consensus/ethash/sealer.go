@@ -32,6 +32,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/mclock"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/types"
 )
@@ -39,11 +40,21 @@ import (
 const (
 	// staleThreshold is the maximum depth of the acceptable stale but valid ethash solution.
 	staleThreshold = 7
+
+	// hashrateTTL is the time after which a remote miner that stopped submitting
+	// its hash rate is dropped from the total.
+	hashrateTTL = 10 * time.Second
 )
 
 var (
-	errNoMiningWork      = errors.New("no mining work available yet")
-	errInvalidSealResult = errors.New("invalid or stale proof-of-work solution")
+	errNoMiningWork     = errors.New("no mining work available yet")
+	errNoPendingWork    = errors.New("work submitted but none pending")
+	errDuplicateSeal    = errors.New("duplicate seal submitted")
+	errNoResultChannel  = errors.New("no sealing result channel")
+	errSealResultUnread = errors.New("sealing result not read by miner")
+	errStaleSealResult  = errors.New("work submitted is too old")
+	errFetchWorkTimeout = errors.New("timeout fetching mining work")
+	errSealerPaused     = errors.New("sealer paused")
 )
 
 // Seal implements consensus.Engine, attempting to find a nonce that satisfies
@@ -189,16 +200,31 @@ search:
 // This is the timeout for HTTP requests to notify external miners.
 const remoteSealerTimeout = 1 * time.Second
 
+// This is the default timeout for external miners waiting on the remote sealer
+// for work, used if none is configured.
+const remoteSealerFetchTimeout = 10 * time.Second
+
 type remoteSealer struct {
-	works        map[common.Hash]*types.Block
+	works map[common.Hash]*types.Block
+
+	// sealed tracks the seal hashes of pending works already accepted. It is not
+	// reset on new work, since remote miners may still submit for the previous
+	// packages; entries are pruned together with their stale works instead.
+	sealed map[common.Hash]struct{}
+
 	rates        map[common.Hash]hashrate
 	currentBlock *types.Block
-	currentWork  [4]string
+	currentWork  *WorkPackage
+	notifiedWork *WorkPackage          // Last work package remote miners were notified of
+	lastNotify   mclock.AbsTime        // Time of the last notification sent to remote miners
+	notifyDelay  <-chan mclock.AbsTime // Fires when a rate limited notification is due
+	paused       bool                  // Whether remote miners are neither notified nor served
 	notifyCtx    context.Context
 	cancelNotify context.CancelFunc // cancels all notification requests
 	reqWG        sync.WaitGroup     // tracks notification request goroutines
 
 	ethash       *Ethash
+	clock        mclock.Clock
 	noverify     bool
 	notifyURLs   []string
 	results      chan<- *types.Block
@@ -207,6 +233,7 @@ type remoteSealer struct {
 	submitWorkCh chan *mineResult // Channel used for remote sealer to submit their mining result
 	fetchRateCh  chan chan uint64 // Channel used to gather submitted hash rate for local or remote sealer.
 	submitRateCh chan *hashrate   // Channel used for remote sealer to submit their mining hashrate
+	pauseCh      chan bool        // Channel used to pause or resume serving remote miners
 	requestExit  chan struct{}
 	exitCh       chan struct{}
 }
@@ -229,7 +256,7 @@ type mineResult struct {
 // hashrate wraps the hash rate submitted by the remote sealer.
 type hashrate struct {
 	id   common.Hash
-	ping time.Time
+	ping mclock.AbsTime
 	rate uint64
 
 	done chan struct{}
@@ -238,29 +265,51 @@ type hashrate struct {
 // sealWork wraps a seal work package for remote sealer.
 type sealWork struct {
 	errc chan error
-	res  chan [4]string
+	res  chan *WorkPackage
+}
+
+// WorkPackage is a mining work package handed out to external miners.
+type WorkPackage struct {
+	PowHash  common.Hash  `json:"powHash"`  // Current block header pow-hash
+	SeedHash common.Hash  `json:"seedHash"` // Seed hash used for DAG
+	Target   common.Hash  `json:"target"`   // Boundary condition ("target"), 2^256/difficulty
+	Number   *hexutil.Big `json:"number"`   // Number of the block being mined
+}
+
+// array flattens the work package into the positional format of the
+// eth_getWork RPC call.
+func (w *WorkPackage) array() [4]string {
+	return [4]string{w.PowHash.Hex(), w.SeedHash.Hex(), w.Target.Hex(), w.Number.String()}
 }
 
 func startRemoteSealer(ethash *Ethash, urls []string, noverify bool) *remoteSealer {
+	s := newRemoteSealer(ethash, urls, noverify, mclock.System{})
+	go s.loop()
+	return s
+}
+
+// newRemoteSealer creates a remote sealer without starting its event loop.
+func newRemoteSealer(ethash *Ethash, urls []string, noverify bool, clock mclock.Clock) *remoteSealer {
 	ctx, cancel := context.WithCancel(context.Background())
-	s := &remoteSealer{
+	return &remoteSealer{
 		ethash:       ethash,
+		clock:        clock,
 		noverify:     noverify,
 		notifyURLs:   urls,
 		notifyCtx:    ctx,
 		cancelNotify: cancel,
 		works:        make(map[common.Hash]*types.Block),
+		sealed:       make(map[common.Hash]struct{}),
 		rates:        make(map[common.Hash]hashrate),
 		workCh:       make(chan *sealTask),
 		fetchWorkCh:  make(chan *sealWork),
 		submitWorkCh: make(chan *mineResult),
 		fetchRateCh:  make(chan chan uint64),
 		submitRateCh: make(chan *hashrate),
+		pauseCh:      make(chan bool),
 		requestExit:  make(chan struct{}),
 		exitCh:       make(chan struct{}),
 	}
-	go s.loop()
-	return s
 }
 
 func (s *remoteSealer) loop() {
@@ -281,11 +330,15 @@ func (s *remoteSealer) loop() {
 			// Note same work can be past twice, happens when changing CPU threads.
 			s.results = work.results
 			s.makeWork(work.block)
-			s.notifyWork()
+			if !s.paused {
+				s.notifyWork()
+			}
 
 		case work := <-s.fetchWorkCh:
 			// Return current mining work to remote miner.
-			if s.currentBlock == nil {
+			if s.paused {
+				work.errc <- errSealerPaused
+			} else if s.currentBlock == nil {
 				work.errc <- errNoMiningWork
 			} else {
 				work.res <- s.currentWork
@@ -293,30 +346,59 @@ func (s *remoteSealer) loop() {
 
 		case result := <-s.submitWorkCh:
 			// Verify submitted PoW solution based on maintained mining blocks.
-			if s.submitWork(result.nonce, result.mixDigest, result.hash) {
-				result.errc <- nil
+			if s.paused {
+				result.errc <- errSealerPaused
 			} else {
-				result.errc <- errInvalidSealResult
+				result.errc <- s.submitWork(result.nonce, result.mixDigest, result.hash)
 			}
 
 		case result := <-s.submitRateCh:
-			// Trace remote sealer's hash rate by submitted value.
-			s.rates[result.id] = hashrate{rate: result.rate, ping: time.Now()}
+			// Trace remote sealer's hash rate by submitted value, a zero
+			// rate removes the miner altogether.
+			if result.rate == 0 {
+				delete(s.rates, result.id)
+			} else {
+				s.rates[result.id] = hashrate{rate: result.rate, ping: s.clock.Now()}
+			}
 			close(result.done)
 
 		case req := <-s.fetchRateCh:
-			// Gather all hash rate submitted by remote sealer.
-			var total uint64
+			// Gather all hash rate submitted by remote sealer, skipping the
+			// stale ones not yet cleared.
+			var (
+				now   = s.clock.Now()
+				total uint64
+			)
 			for _, rate := range s.rates {
+				if now.Sub(rate.ping) > hashrateTTL {
+					continue
+				}
 				// this could overflow
 				total += rate.rate
 			}
 			req <- total
 
+		case <-s.notifyDelay:
+			// Send the latest work package held back by the rate limit.
+			s.notifyDelay = nil
+			if !s.paused {
+				s.notifyWork()
+			}
+
+		case paused := <-s.pauseCh:
+			// Requests received before the transition are served in the old state,
+			// the ones after it in the new one. Catch up on work missed while paused.
+			resumed := s.paused && !paused
+			s.paused = paused
+			if resumed && s.currentWork != nil {
+				s.notifyWork()
+			}
+
 		case <-ticker.C:
 			// Clear stale submitted hash rate.
+			now := s.clock.Now()
 			for id, rate := range s.rates {
-				if time.Since(rate.ping) > 10*time.Second {
+				if now.Sub(rate.ping) > hashrateTTL {
 					delete(s.rates, id)
 				}
 			}
@@ -325,6 +407,7 @@ func (s *remoteSealer) loop() {
 				for hash, block := range s.works {
 					if block.NumberU64()+staleThreshold <= s.currentBlock.NumberU64() {
 						delete(s.works, hash)
+						delete(s.sealed, hash)
 					}
 				}
 			}
@@ -336,18 +419,14 @@ func (s *remoteSealer) loop() {
 }
 
 // makeWork creates a work package for external miner.
-//
-// The work package consists of 3 strings:
-//   result[0], 32 bytes hex encoded current block header pow-hash
-//   result[1], 32 bytes hex encoded seed hash used for DAG
-//   result[2], 32 bytes hex encoded boundary condition ("target"), 2^256/difficulty
-//   result[3], hex encoded block number
 func (s *remoteSealer) makeWork(block *types.Block) {
 	hash := s.ethash.SealHash(block.Header())
-	s.currentWork[0] = hash.Hex()
-	s.currentWork[1] = common.BytesToHash(SeedHash(block.NumberU64())).Hex()
-	s.currentWork[2] = common.BytesToHash(new(big.Int).Div(two256, block.Difficulty()).Bytes()).Hex()
-	s.currentWork[3] = hexutil.EncodeBig(block.Number())
+	s.currentWork = &WorkPackage{
+		PowHash:  hash,
+		SeedHash: common.BytesToHash(SeedHash(block.NumberU64())),
+		Target:   common.BytesToHash(new(big.Int).Div(two256, block.Difficulty()).Bytes()),
+		Number:   (*hexutil.Big)(block.Number()),
+	}
 
 	// Trace the seal work fetched by remote sealer.
 	s.currentBlock = block
@@ -358,14 +437,41 @@ func (s *remoteSealer) makeWork(block *types.Block) {
 // new work to be processed.
 func (s *remoteSealer) notifyWork() {
 	work := s.currentWork
-	blob, _ := json.Marshal(work)
+
+	// Skip the notification if the remote miners already have this work package,
+	// happens when the same block is pushed twice.
+	if s.notifiedWork != nil && s.notifiedWork.PowHash == work.PowHash {
+		return
+	}
+	// Rate limit the notifications if requested, deferring work packages
+	// arriving too fast. Only the latest one is sent once the interval elapsed.
+	if interval := s.ethash.config.NotifyInterval; interval > 0 && s.notifiedWork != nil {
+		if s.notifyDelay != nil {
+			return
+		}
+		if elapsed := s.clock.Now().Sub(s.lastNotify); elapsed < interval {
+			s.ethash.config.Log.Debug("Throttling remote miner notifications", "interval", interval, "delay", interval-elapsed)
+			s.notifyDelay = s.clock.After(interval - elapsed)
+			return
+		}
+	}
+	s.notifiedWork, s.lastNotify = work, s.clock.Now()
+
+	// Encode the JSON payload of the notification. When NotifyObject is
+	// set, it's the named work package, otherwise the legacy [4]string array.
+	var blob []byte
+	if s.ethash.config.NotifyObject {
+		blob, _ = json.Marshal(work)
+	} else {
+		blob, _ = json.Marshal(work.array())
+	}
 	s.reqWG.Add(len(s.notifyURLs))
 	for _, url := range s.notifyURLs {
 		go s.sendNotification(s.notifyCtx, url, blob, work)
 	}
 }
 
-func (s *remoteSealer) sendNotification(ctx context.Context, url string, json []byte, work [4]string) {
+func (s *remoteSealer) sendNotification(ctx context.Context, url string, json []byte, work *WorkPackage) {
 	defer s.reqWG.Done()
 
 	req, err := http.NewRequest("POST", url, bytes.NewReader(json))
@@ -382,24 +488,29 @@ func (s *remoteSealer) sendNotification(ctx context.Context, url string, json []
 	if err != nil {
 		s.ethash.config.Log.Warn("Failed to notify remote miner", "err", err)
 	} else {
-		s.ethash.config.Log.Trace("Notified remote miner", "miner", url, "hash", work[0], "target", work[2])
+		s.ethash.config.Log.Trace("Notified remote miner", "miner", url, "hash", work.PowHash, "target", work.Target)
 		resp.Body.Close()
 	}
 }
 
-// submitWork verifies the submitted pow solution, returning
-// whether the solution was accepted or not (not can be both a bad pow as well as
+// submitWork verifies the submitted pow solution, returning nil if the solution
+// was accepted, or the reason of the rejection otherwise (a bad pow as well as
 // any other error, like no pending work or stale mining result).
-func (s *remoteSealer) submitWork(nonce types.BlockNonce, mixDigest common.Hash, sealhash common.Hash) bool {
+func (s *remoteSealer) submitWork(nonce types.BlockNonce, mixDigest common.Hash, sealhash common.Hash) error {
 	if s.currentBlock == nil {
 		s.ethash.config.Log.Error("Pending work without block", "sealhash", sealhash)
-		return false
+		return errNoMiningWork
 	}
 	// Make sure the work submitted is present
 	block := s.works[sealhash]
 	if block == nil {
 		s.ethash.config.Log.Warn("Work submitted but none pending", "sealhash", sealhash, "curnumber", s.currentBlock.NumberU64())
-		return false
+		return errNoPendingWork
+	}
+	// Make sure the work wasn't already sealed by an earlier submission
+	if _, ok := s.sealed[sealhash]; ok {
+		s.ethash.config.Log.Warn("Duplicate seal submitted", "number", block.NumberU64(), "sealhash", sealhash)
+		return errDuplicateSeal
 	}
 	// Verify the correctness of submitted result.
 	header := block.Header()
@@ -410,13 +521,13 @@ func (s *remoteSealer) submitWork(nonce types.BlockNonce, mixDigest common.Hash,
 	if !s.noverify {
 		if err := s.ethash.verifySeal(nil, header, true); err != nil {
 			s.ethash.config.Log.Warn("Invalid proof-of-work submitted", "sealhash", sealhash, "elapsed", common.PrettyDuration(time.Since(start)), "err", err)
-			return false
+			return err
 		}
 	}
 	// Make sure the result channel is assigned.
 	if s.results == nil {
 		s.ethash.config.Log.Warn("Ethash result channel is empty, submitted mining result is rejected")
-		return false
+		return errNoResultChannel
 	}
 	s.ethash.config.Log.Trace("Verified correct proof-of-work", "sealhash", sealhash, "elapsed", common.PrettyDuration(time.Since(start)))
 
@@ -427,14 +538,15 @@ func (s *remoteSealer) submitWork(nonce types.BlockNonce, mixDigest common.Hash,
 	if solution.NumberU64()+staleThreshold > s.currentBlock.NumberU64() {
 		select {
 		case s.results <- solution:
+			s.sealed[sealhash] = struct{}{}
 			s.ethash.config.Log.Debug("Work submitted is acceptable", "number", solution.NumberU64(), "sealhash", sealhash, "hash", solution.Hash())
-			return true
+			return nil
 		default:
 			s.ethash.config.Log.Warn("Sealing result is not read by miner", "mode", "remote", "sealhash", sealhash)
-			return false
+			return errSealResultUnread
 		}
 	}
 	// The submitted block is too old to accept, drop it.
 	s.ethash.config.Log.Warn("Work submitted is too old", "number", solution.NumberU64(), "sealhash", sealhash, "hash", solution.Hash())
-	return false
+	return errStaleSealResult
 }
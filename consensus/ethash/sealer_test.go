@@ -26,11 +26,51 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/mclock"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/testlog"
 	"github.com/ethereum/go-ethereum/log"
 )
 
+// startNotifySink starts a web server capturing the work packages remote miners
+// are notified of, in either the array or the JSON object format.
+func startNotifySink(t *testing.T) (*httptest.Server, <-chan *WorkPackage) {
+	t.Helper()
+
+	sink := make(chan *WorkPackage, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		blob, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			t.Errorf("failed to read miner notification: %v", err)
+		}
+		work := new(WorkPackage)
+		if len(blob) > 0 && blob[0] == '[' {
+			var array [3]string
+			if err := json.Unmarshal(blob, &array); err != nil {
+				t.Errorf("failed to unmarshal miner notification: %v", err)
+			}
+			work.PowHash = common.HexToHash(array[0])
+			work.SeedHash = common.HexToHash(array[1])
+			work.Target = common.HexToHash(array[2])
+		} else if err := json.Unmarshal(blob, work); err != nil {
+			t.Errorf("failed to unmarshal miner notification: %v", err)
+		}
+		sink <- work
+	}))
+	return server, sink
+}
+
+// newTestRemoteSealer creates a fake ethash engine with a running remote sealer
+// notifying the given urls and driven by the given clock.
+func newTestRemoteSealer(t *testing.T, urls []string, clock mclock.Clock) *Ethash {
+	t.Helper()
+
+	ethash := NewFaker()
+	ethash.remote = newRemoteSealer(ethash, urls, false, clock)
+	go ethash.remote.loop()
+	return ethash
+}
+
 // Tests whether remote HTTP servers are correctly notified of new work.
 func TestRemoteNotify(t *testing.T) {
 	// Start a simple web server to capture notifications.
@@ -74,6 +114,162 @@ func TestRemoteNotify(t *testing.T) {
 	}
 }
 
+// Tests whether remote HTTP servers are correctly notified of new work in the
+// JSON object format.
+func TestRemoteNotifyObject(t *testing.T) {
+	server, sink := startNotifySink(t)
+	defer server.Close()
+
+	// Create the custom ethash engine.
+	ethash := NewTester([]string{server.URL}, false)
+	ethash.config.NotifyObject = true
+	defer ethash.Close()
+
+	// Stream a work task and ensure the notification bubbles out.
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(100)}
+	block := types.NewBlockWithHeader(header)
+
+	ethash.Seal(nil, block, nil, nil)
+	select {
+	case work := <-sink:
+		if want := ethash.SealHash(header); work.PowHash != want {
+			t.Errorf("work packet hash mismatch: have %x, want %x", work.PowHash, want)
+		}
+		if want := common.BytesToHash(SeedHash(header.Number.Uint64())); work.SeedHash != want {
+			t.Errorf("work packet seed mismatch: have %x, want %x", work.SeedHash, want)
+		}
+		target := new(big.Int).Div(new(big.Int).Lsh(big.NewInt(1), 256), header.Difficulty)
+		if want := common.BytesToHash(target.Bytes()); work.Target != want {
+			t.Errorf("work packet target mismatch: have %x, want %x", work.Target, want)
+		}
+		if work.Number.ToInt().Cmp(header.Number) != 0 {
+			t.Errorf("work packet number mismatch: have %v, want %v", work.Number, header.Number)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("notification timed out")
+	}
+}
+
+// Tests that sealing the same block twice only notifies remote miners once.
+func TestRemoteNotifyDuplicate(t *testing.T) {
+	server, sink := startNotifySink(t)
+	defer server.Close()
+
+	// Create the custom ethash engine.
+	ethash := NewTester([]string{server.URL}, false)
+	defer ethash.Close()
+
+	// Stream the same work task twice and ensure only one notification bubbles out.
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(100)}
+	block := types.NewBlockWithHeader(header)
+
+	ethash.Seal(nil, block, nil, nil)
+	ethash.Seal(nil, block, nil, nil)
+
+	select {
+	case work := <-sink:
+		if want := ethash.SealHash(header); work.PowHash != want {
+			t.Errorf("work packet hash mismatch: have %x, want %x", work.PowHash, want)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("notification timed out")
+	}
+	select {
+	case <-sink:
+		t.Errorf("duplicate notification received")
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+// Tests that a paused remote sealer neither notifies nor serves remote miners,
+// and catches up on the missed work once resumed.
+func TestRemoteSealerPause(t *testing.T) {
+	server, sink := startNotifySink(t)
+	defer server.Close()
+
+	ethash := NewTester([]string{server.URL}, true)
+	defer ethash.Close()
+	api := &API{ethash}
+
+	if err := ethash.PauseRemoteSealer(); err != nil {
+		t.Fatalf("failed to pause remote sealer: %v", err)
+	}
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(100000000)}
+	results := make(chan *types.Block, 1)
+	ethash.Seal(nil, types.NewBlockWithHeader(header), results, nil)
+
+	if _, err := api.GetWork(); err != errSealerPaused {
+		t.Errorf("paused fetch error mismatch: have %v, want %v", err, errSealerPaused)
+	}
+	if api.SubmitWork(types.BlockNonce{0x01}, ethash.SealHash(header), common.HexToHash("deadbeef")) {
+		t.Errorf("submission accepted by paused sealer")
+	}
+	select {
+	case <-sink:
+		t.Fatalf("paused sealer notified remote miner")
+	case <-time.After(500 * time.Millisecond):
+	}
+	// Resume and ensure the work is announced and served.
+	if err := ethash.ResumeRemoteSealer(); err != nil {
+		t.Fatalf("failed to resume remote sealer: %v", err)
+	}
+	select {
+	case work := <-sink:
+		if want := ethash.SealHash(header); work.PowHash != want {
+			t.Errorf("work packet hash mismatch: have %x, want %x", work.PowHash, want)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("notification timed out")
+	}
+	if work, err := api.GetWork(); err != nil || work[0] != ethash.SealHash(header).Hex() {
+		t.Errorf("resumed fetch mismatch: have %v, %v", work, err)
+	}
+	if !api.SubmitWork(types.BlockNonce{0x01}, ethash.SealHash(header), common.HexToHash("deadbeef")) {
+		t.Errorf("submission rejected by resumed sealer")
+	}
+}
+
+// Tests that notifications are rate limited, coalescing work packages arriving
+// too fast into a single notification of the latest one.
+func TestRemoteNotifyInterval(t *testing.T) {
+	server, sink := startNotifySink(t)
+	defer server.Close()
+
+	// Create a remote sealer running on a simulated clock.
+	clock := new(mclock.Simulated)
+
+	ethash := newTestRemoteSealer(t, []string{server.URL}, clock)
+	ethash.config.NotifyInterval = time.Second
+	defer ethash.Close()
+
+	headers := make([]*types.Header, 3)
+	for i := range headers {
+		headers[i] = &types.Header{Number: big.NewInt(int64(i + 1)), Difficulty: big.NewInt(100)}
+		ethash.remote.workCh <- &sealTask{block: types.NewBlockWithHeader(headers[i])}
+	}
+	// Only the first package goes out immediately, the last one after the interval.
+	expect := func(header *types.Header) {
+		select {
+		case work := <-sink:
+			if want := ethash.SealHash(header); work.PowHash != want {
+				t.Errorf("work packet hash mismatch: have %x, want %x", work.PowHash, want)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("notification timed out")
+		}
+	}
+	expect(headers[0])
+	clock.WaitForTimers(1)
+	clock.Run(time.Second)
+	expect(headers[2])
+
+	select {
+	case work := <-sink:
+		t.Errorf("unexpected notification: %v", work)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
 // Tests that pushing work packages fast to the miner doesn't cause any data race
 // issues in the notifications.
 func TestRemoteMultiNotify(t *testing.T) {
@@ -202,3 +398,31 @@ func TestStaleSubmission(t *testing.T) {
 		}
 	}
 }
+
+// Tests that a solution for an already sealed work package is rejected.
+func TestDuplicateSubmission(t *testing.T) {
+	ethash := NewTester(nil, true)
+	defer ethash.Close()
+	api := &API{ethash}
+
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(100000000)}
+	results := make(chan *types.Block, 2)
+	ethash.Seal(nil, types.NewBlockWithHeader(header), results, nil)
+
+	fakeNonce, fakeDigest := types.BlockNonce{0x01, 0x02, 0x03}, common.HexToHash("deadbeef")
+	if !api.SubmitWork(fakeNonce, ethash.SealHash(header), fakeDigest) {
+		t.Fatalf("first submission rejected")
+	}
+	if api.SubmitWork(fakeNonce, ethash.SealHash(header), fakeDigest) {
+		t.Errorf("duplicate submission accepted")
+	}
+	if api.SubmitWork(types.BlockNonce{0x04}, ethash.SealHash(header), common.HexToHash("cafebabe")) {
+		t.Errorf("duplicate submission with different nonce accepted")
+	}
+	if res := api.SubmitWorkVerbose(fakeNonce, ethash.SealHash(header), fakeDigest); res.Accepted || res.Reason != errDuplicateSeal.Error() {
+		t.Errorf("duplicate submission result mismatch: have %+v, want reason %q", res, errDuplicateSeal)
+	}
+	if len(results) != 1 {
+		t.Errorf("sealed block count mismatch: have %d, want 1", len(results))
+	}
+}
@@ -30,6 +30,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -40,6 +41,10 @@ import (
 	"github.com/ethereum/go-ethereum/trie"
 )
 
+// errNoRemoteSealer is returned by the remote sealer controls if the node is
+// not running the ethash consensus engine.
+var errNoRemoteSealer = errors.New("remote sealer requires the ethash engine")
+
 // PublicEthereumAPI provides an API to access Ethereum full node-related
 // information.
 type PublicEthereumAPI struct {
@@ -154,6 +159,25 @@ func (api *PrivateMinerAPI) GetHashrate() uint64 {
 	return api.e.miner.HashRate()
 }
 
+// PauseRemoteSealer stops serving remote ethash miners until ResumeRemoteSealer
+// is called: no work notifications are sent, fetches and submissions are rejected.
+func (api *PrivateMinerAPI) PauseRemoteSealer() error {
+	engine, ok := api.e.engine.(*ethash.Ethash)
+	if !ok {
+		return errNoRemoteSealer
+	}
+	return engine.PauseRemoteSealer()
+}
+
+// ResumeRemoteSealer resumes serving remote ethash miners after PauseRemoteSealer.
+func (api *PrivateMinerAPI) ResumeRemoteSealer() error {
+	engine, ok := api.e.engine.(*ethash.Ethash)
+	if !ok {
+		return errNoRemoteSealer
+	}
+	return engine.ResumeRemoteSealer()
+}
+
 // PrivateAdminAPI is the collection of Ethereum full node-related APIs
 // exposed over the private admin endpoint.
 type PrivateAdminAPI struct {
@@ -230,6 +230,9 @@ func CreateConsensusEngine(stack *node.Node, chainConfig *params.ChainConfig, co
 			DatasetsInMem:    config.DatasetsInMem,
 			DatasetsOnDisk:   config.DatasetsOnDisk,
 			DatasetsLockMmap: config.DatasetsLockMmap,
+			NotifyObject:     config.NotifyObject,
+			NotifyInterval:   config.NotifyInterval,
+			FetchTimeout:     config.FetchTimeout,
 		}, notify, noverify)
 		engine.SetThreads(-1) // Disable CPU mining
 		return engine
@@ -127,16 +127,31 @@ web3._extend({
 			call: 'ethash_getWork',
 			params: 0
 		}),
+		new web3._extend.Method({
+			name: 'getWorkDetailed',
+			call: 'ethash_getWorkDetailed',
+			params: 0
+		}),
 		new web3._extend.Method({
 			name: 'getHashrate',
 			call: 'ethash_getHashrate',
 			params: 0
 		}),
+		new web3._extend.Method({
+			name: 'getHashrateDetailed',
+			call: 'ethash_getHashrateDetailed',
+			params: 0
+		}),
 		new web3._extend.Method({
 			name: 'submitWork',
 			call: 'ethash_submitWork',
 			params: 3,
 		}),
+		new web3._extend.Method({
+			name: 'submitWorkVerbose',
+			call: 'ethash_submitWorkVerbose',
+			params: 3,
+		}),
 		new web3._extend.Method({
 			name: 'submitHashRate',
 			call: 'ethash_submitHashRate',
@@ -621,6 +636,14 @@ web3._extend({
 			name: 'getHashrate',
 			call: 'miner_getHashrate'
 		}),
+		new web3._extend.Method({
+			name: 'pauseRemoteSealer',
+			call: 'miner_pauseRemoteSealer'
+		}),
+		new web3._extend.Method({
+			name: 'resumeRemoteSealer',
+			call: 'miner_resumeRemoteSealer'
+		}),
 	],
 	properties: []
 });